@@ -0,0 +1,151 @@
+// Command compare roda as variantes channel-sequencer, sync.Mutex e
+// sync/atomic com a mesma configuração (numWorkers/loopIntensity/
+// executionTimes) usando o harness compartilhado em internal/bench, e
+// imprime os resultados lado a lado. É o binário que dá nome ao repositório:
+// a comparação real entre CSP (canais) e os primitivos de mutex/atomic do
+// pacote sync.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/bench"
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/variants"
+)
+
+func main() {
+	var numWorkers, loopIntensity, executionTimes int
+	var csvPath string
+
+	flag.IntVar(&numWorkers, "workers", 1000, "número de workers/goroutines por rodada")
+	flag.IntVar(&loopIntensity, "loop-intensity", 100, "intensidade do loop (carga de trabalho) na seção crítica")
+	flag.IntVar(&executionTimes, "rounds", 10, "número de rodadas de execução por variante")
+	flag.StringVar(&csvPath, "csv", "", "caminho para gravar os resultados em CSV (vazio desabilita)")
+	flag.Parse()
+
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	cfg := bench.Config{
+		NumWorkers:     numWorkers,
+		LoopIntensity:  loopIntensity,
+		ExecutionTimes: executionTimes,
+	}
+
+	candidates := []bench.Benchmark{
+		&variants.ChannelSequencer{},
+		&variants.MutexCounter{},
+		&variants.AtomicCounter{},
+	}
+
+	results := make([]bench.Results, 0, len(candidates))
+	for _, b := range candidates {
+		fmt.Printf("Executando variante: %s\n", b.Name())
+		results = append(results, bench.Run(b, cfg))
+	}
+
+	if len(results) > 0 {
+		quota := results[0].CPUQuota
+		fmt.Printf("Cota de CPU detectada: %.2f núcleos (%s)\n", quota.Cores, quota.Source)
+	}
+
+	printComparisonTable(results, int64(numWorkers))
+
+	if csvPath != "" {
+		if err := writeComparisonCSV(csvPath, results, int64(numWorkers)); err != nil {
+			fmt.Fprintf(os.Stderr, "csv: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Resultados gravados em %s\n", csvPath)
+	}
+}
+
+// printComparisonTable imprime uma tabela com uma coluna por variante e uma
+// linha por métrica agregada, alinhada com text/tabwriter.
+func printComparisonTable(results []bench.Results, expectedCounter int64) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "\n--- Comparação CSP vs Mutex vs Atomic ---\n")
+
+	fmt.Fprint(w, "Métrica")
+	for _, r := range results {
+		fmt.Fprintf(w, "\t%s", r.Name)
+	}
+	fmt.Fprintln(w)
+
+	row := func(label string, value func(bench.Results) string) {
+		fmt.Fprint(w, label)
+		for _, r := range results {
+			fmt.Fprintf(w, "\t%s", value(r))
+		}
+		fmt.Fprintln(w)
+	}
+
+	row("Tempo médio/rodada (s)", func(r bench.Results) string {
+		return fmt.Sprintf("%.8f", r.AverageElapsed())
+	})
+	row("Tempo médio seção crítica (s)", func(r bench.Results) string {
+		return fmt.Sprintf("%.8f", r.AverageCriticalTime())
+	})
+	row("Throughput médio (ops/s)", func(r bench.Results) string {
+		return fmt.Sprintf("%.2f", r.AverageThroughput())
+	})
+	row("CPU médio (% de 1 núcleo)", func(r bench.Results) string {
+		return fmt.Sprintf("%.2f", r.AverageCPUPercent())
+	})
+	row("CPU médio normalizado (%)", func(r bench.Results) string {
+		return fmt.Sprintf("%.2f", r.AverageCPUNormalized())
+	})
+	row("Houve race condition?", func(r bench.Results) string {
+		return strconv.FormatBool(r.RaceConditionOccurred(expectedCounter))
+	})
+}
+
+// writeComparisonCSV grava as mesmas métricas agregadas de
+// printComparisonTable em formato CSV, uma linha por variante.
+func writeComparisonCSV(path string, results []bench.Results, expectedCounter int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("criar arquivo: %w", err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	defer writer.Flush()
+
+	header := []string{
+		"variante",
+		"tempo_medio_rodada_s",
+		"tempo_medio_secao_critica_s",
+		"throughput_medio_ops_s",
+		"cpu_medio_percent",
+		"cpu_medio_normalizado_percent",
+		"race_condition",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("escrever cabeçalho: %w", err)
+	}
+
+	for _, r := range results {
+		record := []string{
+			r.Name,
+			strconv.FormatFloat(r.AverageElapsed(), 'f', -1, 64),
+			strconv.FormatFloat(r.AverageCriticalTime(), 'f', -1, 64),
+			strconv.FormatFloat(r.AverageThroughput(), 'f', -1, 64),
+			strconv.FormatFloat(r.AverageCPUPercent(), 'f', -1, 64),
+			strconv.FormatFloat(r.AverageCPUNormalized(), 'f', -1, 64),
+			strconv.FormatBool(r.RaceConditionOccurred(expectedCounter)),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("escrever linha de %s: %w", r.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/trace"
+
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/bench"
+)
+
+// --- Variáveis Globais de Benchmark ---
+var (
+	// NUM_CPU define o número de threads do S.O. que podem executar código
+	// simultaneamente. É usado para configurar GOMAXPROCS.
+	NUM_CPU = 16
+
+	// junkValue é usado para garantir que o compilador não otimize e remova
+	// o loop de processamento, assegurando que o trabalho de CPU simulado aconteça.
+	// Só as rodadas do modo de varredura (-sweep) o escrevem; as rodadas das
+	// topologias guardam o valor em shard.junk, então o "Valor final de Junk"
+	// de printFinalReport é sempre 0.
+	junkValue int64 = 0
+
+	// --- Variáveis de Configuração de Topologia e Trace ---
+
+	// traceEnabled liga a captura de um arquivo runtime/trace por rodada
+	// (flag -trace).
+	traceEnabled bool
+
+	// traceDir é o diretório onde os arquivos de trace por rodada são
+	// gravados (flag -trace-dir).
+	traceDir string
+
+	// numShards é o número de sequencers usados pelas topologias sharded e
+	// pipelined (flag -shards). Ignorado pela topologia fanin, que sempre
+	// usa um único shard.
+	numShards int
+)
+
+// --- Flags do Modo de Varredura (-sweep) ---
+var (
+	// sweepEnabled liga o modo de varredura (flag -sweep): em vez de rodar
+	// a topologia configurada, varre tamanho de buffer do canal, GOMAXPROCS
+	// e número de workers.
+	sweepEnabled bool
+
+	// sweepOutput é o caminho onde a grade de varredura é gravada (flag
+	// -sweep-output). Vazio desabilita a gravação em arquivo.
+	sweepOutput string
+
+	// sweepFormat é o formato do arquivo gravado em sweepOutput: "json" ou
+	// "csv" (flag -sweep-format).
+	sweepFormat string
+)
+
+func main() {
+	// Define o número máximo de threads do S.O. a serem usadas pelo programa Go.
+	runtime.GOMAXPROCS(NUM_CPU)
+
+	// --- Flags de Topologia e Trace ---
+	var topologyFlag string
+	flag.BoolVar(&traceEnabled, "trace", false, "grava um arquivo runtime/trace por rodada")
+	flag.StringVar(&traceDir, "trace-dir", "traces", "diretório onde os arquivos de trace por rodada são gravados")
+	flag.StringVar(&topologyFlag, "topology", string(topologyFanIn), "topologia do benchmark: fanin, sharded ou pipelined")
+	flag.IntVar(&numShards, "shards", 4, "número de sequencers/shards usados pelas topologias sharded e pipelined")
+	flag.BoolVar(&sweepEnabled, "sweep", false, "roda o modo de varredura (tamanho de buffer do canal x GOMAXPROCS x workers) em vez da topologia configurada")
+	flag.StringVar(&sweepOutput, "sweep-output", "sweep.json", "caminho onde a grade de varredura é gravada (vazio desabilita)")
+	flag.StringVar(&sweepFormat, "sweep-format", "json", "formato do arquivo de saída da varredura: json ou csv")
+	flag.Parse()
+
+	topology, err := parseTopologyKind(topologyFlag)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if topology != topologyFanIn && numShards < 1 {
+		fmt.Printf("número de shards inválido: %d (use um valor >= 1)\n", numShards)
+		os.Exit(1)
+	}
+
+	// --- Coleta de Inputs do Usuário ---
+	var numWorkers, loopIntensity, executionTimes int
+	fmt.Println("--- Configuração do Benchmark (Go Channels) ---")
+	fmt.Print("Número de Workers/Goroutines: ")
+	fmt.Scanln(&numWorkers)
+	fmt.Print("Intensidade do Loop (carga de trabalho): ")
+	fmt.Scanln(&loopIntensity)
+	fmt.Print("Número de rodadas de execução: ")
+	fmt.Scanln(&executionTimes)
+
+	if sweepEnabled {
+		runSweepMode(numWorkers, loopIntensity, executionTimes)
+		return
+	}
+
+	fmt.Printf("Topologia: %s", topology)
+	if topology != topologyFanIn {
+		fmt.Printf(" (shards=%d)", numShards)
+	}
+	fmt.Println()
+	if traceEnabled {
+		fmt.Printf("Trace habilitado, gravando em %q\n", traceDir)
+	}
+
+	cfg := bench.Config{
+		NumWorkers:     numWorkers,
+		LoopIntensity:  loopIntensity,
+		ExecutionTimes: executionTimes,
+	}
+
+	b := &topologyBenchmark{
+		kind:         topology,
+		numShards:    numShards,
+		traceEnabled: traceEnabled,
+		traceDir:     traceDir,
+	}
+
+	// --- Aquecimento e Benchmark ---
+	// O harness compartilhado (bench.Run) descarta a primeira rodada como
+	// aquecimento antes de começar a coletar métricas.
+	fmt.Println("Aquecendo a Go Runtime e começando o benchmark...")
+	results := bench.Run(b, cfg)
+
+	printFinalReport(results, numWorkers)
+}
+
+// printFinalReport imprime o relatório por rodada e as médias finais do
+// benchmark, no mesmo formato usado antes da extração do harness
+// compartilhado.
+func printFinalReport(r bench.Results, numWorkers int) {
+	fmt.Println("\n--- Resultados Finais (Go Channels) ---")
+	fmt.Printf("Cota de CPU detectada: %.2f núcleos (%s)\n", r.CPUQuota.Cores, r.CPUQuota.Source)
+	fmt.Println("Valores finais do contador por rodada:", r.Counters)
+	fmt.Printf("Tempos totais por rodada (s): %v\n", bench.DurationsToSeconds(r.ElapsedTimes))
+	fmt.Printf("Tempos de seção crítica por rodada (s): %v\n", bench.DurationsToSeconds(r.CriticalTimes))
+	fmt.Printf("Throughputs por rodada (ops/s): %v\n", r.Throughputs)
+	fmt.Printf("Uso de CPU por rodada (%% de 1 núcleo): %v\n", r.CPUUsage)
+	bench.PrintRuntimeMetricsReport(r.RuntimeMetrics)
+	fmt.Println("Valor final de Junk:", junkValue)
+	fmt.Printf("\n--- Médias ---\n")
+	fmt.Printf("Houve race condition?: %t\n", r.RaceConditionOccurred(int64(numWorkers)))
+	fmt.Printf("Tempo médio por rodada: %.8fs\n", r.AverageElapsed())
+	fmt.Printf("Tempo médio de seção crítica por rodada: %.8fs\n", r.AverageCriticalTime())
+	fmt.Printf("Throughput médio global: %.2f ops/s\n", r.AverageThroughput())
+	fmt.Printf("Uso de CPU médio (relativo a 1 núcleo): %.2f %%\n", r.AverageCPUPercent())
+	fmt.Printf("Utilização de CPU média (normalizada para todos os núcleos): %.2f %%\n", r.AverageCPUNormalized())
+}
+
+// topologyBenchmark adapta runTopologyRound à interface bench.Benchmark,
+// para que este binário também use o harness compartilhado (aquecimento,
+// coleta de runtime/metrics e uso de CPU). Ela também decide, a cada
+// chamada de RunRound, se a rodada deve ser envolvida em uma captura de
+// runtime/trace: a primeira chamada é sempre o aquecimento descartado por
+// bench.Run e nunca é traçada.
+type topologyBenchmark struct {
+	kind         topologyKind
+	numShards    int
+	traceEnabled bool
+	traceDir     string
+
+	cfg        bench.Config
+	warmupDone bool
+	roundIndex int
+}
+
+func (t *topologyBenchmark) Name() string { return "csp-" + string(t.kind) }
+
+func (t *topologyBenchmark) Setup(cfg bench.Config) { t.cfg = cfg }
+
+func (t *topologyBenchmark) Teardown() {}
+
+func (t *topologyBenchmark) RunRound(parentCtx context.Context) bench.RoundResult {
+	if !t.warmupDone {
+		t.warmupDone = true
+		outcome := runTopologyRound(t.kind, t.cfg.NumWorkers, t.cfg.LoopIntensity, t.numShards, parentCtx)
+		return bench.RoundResult{FinalCount: sumCounters(outcome.shardCounters), CriticalTime: outcome.criticalTime}
+	}
+
+	// Captura um arquivo runtime/trace desta rodada quando -trace está
+	// habilitada; caso contrário stopTrace é um no-op.
+	stopTrace := startRoundTrace(t.traceEnabled, t.traceDir, t.roundIndex)
+
+	// ctx carrega a tarefa de trace desta rodada, usada para agrupar as
+	// regiões/eventos emitidos pelos shards, pelo hasher (topologia
+	// pipelined) e pelos workers.
+	ctx, task := trace.NewTask(parentCtx, fmt.Sprintf("round-%d", t.roundIndex))
+	t.roundIndex++
+
+	outcome := runTopologyRound(t.kind, t.cfg.NumWorkers, t.cfg.LoopIntensity, t.numShards, ctx)
+
+	task.End()
+	stopTrace()
+
+	return bench.RoundResult{FinalCount: sumCounters(outcome.shardCounters), CriticalTime: outcome.criticalTime}
+}
+
+// sumCounters soma o counter final de todos os shards de uma rodada, usado
+// para verificar se a rodada processou exatamente numWorkers requisições
+// (sem perdas nem condições de corrida).
+func sumCounters(shardCounters []int64) int64 {
+	var total int64
+	for _, c := range shardCounters {
+		total += c
+	}
+	return total
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// unboundedBufferSize identifica, na grade de varredura, a variante de
+// canal "sem limite de buffer" (implementada via uma fila em slice, não um
+// buffer de canal de verdade — Go não suporta canais com capacidade
+// ilimitada).
+const unboundedBufferSize = -1
+
+// SweepCell identifica um ponto da grade de varredura: tamanho de buffer do
+// canal, GOMAXPROCS e número de workers.
+type SweepCell struct {
+	Workers    int `json:"workers"`
+	BufferSize int `json:"buffer_size"` // unboundedBufferSize (-1) = sem limite
+	GOMAXPROCS int `json:"gomaxprocs"`
+}
+
+// SweepStat resume uma amostra de medições: média, desvio padrão amostral e
+// o intervalo de confiança de 95%.
+type SweepStat struct {
+	Mean   float64 `json:"mean"`
+	Stddev float64 `json:"stddev"`
+	CILow  float64 `json:"ci95_low"`
+	CIHigh float64 `json:"ci95_high"`
+}
+
+// SweepResult é o resultado agregado de um ponto da grade, após rodar
+// 'executionTimes' rodadas nele.
+type SweepResult struct {
+	Cell         SweepCell `json:"cell"`
+	Throughput   SweepStat `json:"throughput_ops_s"`
+	CriticalTime SweepStat `json:"critical_time_s"`
+}
+
+// runSweepMode roda o modo de varredura completo e imprime/grava seus
+// resultados: é o ponto de entrada chamado por main quando -sweep está
+// habilitada, usando numWorkers como o topo da escala geométrica de
+// contagens de workers.
+func runSweepMode(numWorkers, loopIntensity, executionTimes int) {
+	fmt.Printf("Varredura: buffer x GOMAXPROCS (1..%d) x workers (até %d), %d rodadas por ponto\n",
+		runtime.NumCPU(), numWorkers, executionTimes)
+
+	results := runSweep(loopIntensity, executionTimes, numWorkers)
+	printSweepTable(results)
+
+	if sweepOutput == "" {
+		return
+	}
+
+	var err error
+	switch sweepFormat {
+	case "csv":
+		err = writeSweepCSV(sweepOutput, results)
+	default:
+		err = writeSweepJSON(sweepOutput, results)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "varredura: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Resultados da varredura gravados em %s\n", sweepOutput)
+}
+
+// runSweep varre o produto cartesiano de tamanho de buffer do canal
+// (0, 1, N/4, N e sem limite, onde N é o número de workers do ponto),
+// GOMAXPROCS (1 até runtime.NumCPU()) e número de workers (escala
+// geométrica até maxWorkers), rodando 'executionTimes' rodadas em cada
+// ponto e agregando throughput e tempo de seção crítica.
+func runSweep(loopIntensity, executionTimes, maxWorkers int) []SweepResult {
+	originalGOMAXPROCS := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(originalGOMAXPROCS)
+
+	workerCounts := geometricWorkerCounts(maxWorkers)
+	var results []SweepResult
+
+	for gp := 1; gp <= runtime.NumCPU(); gp++ {
+		runtime.GOMAXPROCS(gp)
+
+		for _, workers := range workerCounts {
+			for _, bufferSize := range bufferSizesFor(workers) {
+				throughputs := make([]float64, 0, executionTimes)
+				criticalSeconds := make([]float64, 0, executionTimes)
+
+				for r := 0; r < executionTimes; r++ {
+					crit, _, elapsed := runSweepRound(workers, loopIntensity, bufferSize)
+					throughputs = append(throughputs, float64(workers)/elapsed.Seconds())
+					criticalSeconds = append(criticalSeconds, crit.Seconds())
+				}
+
+				results = append(results, SweepResult{
+					Cell:         SweepCell{Workers: workers, BufferSize: bufferSize, GOMAXPROCS: gp},
+					Throughput:   computeStat(throughputs),
+					CriticalTime: computeStat(criticalSeconds),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// geometricWorkerCounts gera uma escala geométrica de contagens de workers
+// (potências de 2) de 1 até maxWorkers, sempre incluindo maxWorkers.
+func geometricWorkerCounts(maxWorkers int) []int {
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
+
+	counts := make([]int, 0)
+	for w := 1; w < maxWorkers; w *= 2 {
+		counts = append(counts, w)
+	}
+	counts = append(counts, maxWorkers)
+
+	return counts
+}
+
+// bufferSizesFor retorna os tamanhos de buffer a testar para um dado número
+// de workers: 0 (sem buffer), 1, N/4, N e sem limite.
+func bufferSizesFor(workers int) []int {
+	quarter := workers / 4
+	if quarter < 1 {
+		quarter = 1
+	}
+
+	return dedupInts([]int{0, 1, quarter, workers, unboundedBufferSize})
+}
+
+// dedupInts remove duplicatas de 'values', preservando a primeira
+// ocorrência de cada valor.
+func dedupInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	out := make([]int, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// runSweepRound executa uma única rodada de varredura com um canal do
+// tamanho de buffer dado (ou sem limite, via newUnboundedChannel) e retorna
+// o tempo de seção crítica acumulado, o counter final e o tempo decorrido
+// desde o primeiro worker disparado até o sequencer terminar de processar
+// todas as requisições.
+func runSweepRound(workers, loopIntensity, bufferSize int) (criticalTime time.Duration, finalCount int64, elapsed time.Duration) {
+	var sendCh chan<- int64
+	var recvCh <-chan int64
+	var closeSend func()
+
+	if bufferSize == unboundedBufferSize {
+		in, out := newUnboundedChannel()
+		sendCh, recvCh = in, out
+		closeSend = func() { close(in) }
+	} else {
+		ch := make(chan int64, bufferSize)
+		sendCh, recvCh = ch, ch
+		closeSend = func() { close(ch) }
+	}
+
+	var counter int64
+
+	var seqWg sync.WaitGroup
+	seqWg.Add(1)
+	go func() {
+		defer seqWg.Done()
+		for requestSeed := range recvCh {
+			start := time.Now()
+
+			result := requestSeed
+			for i := 0; i < loopIntensity; i++ {
+				result = result*31 + int64(i)
+			}
+			junkValue = result
+			counter++
+
+			criticalTime += time.Since(start)
+		}
+	}()
+
+	var workersWg sync.WaitGroup
+	workersWg.Add(workers)
+	start := time.Now()
+	for j := 0; j < workers; j++ {
+		go func(seed int64) {
+			defer workersWg.Done()
+			sendCh <- seed
+		}(time.Now().UnixNano())
+	}
+	workersWg.Wait()
+
+	// elapsed cobre a rodada inteira, incluindo o sequencer esvaziar o
+	// buffer (ou a fila sem limite) após o último worker enviar — não
+	// apenas o envio, que com um buffer grande retorna quase
+	// instantaneamente e não refletiria o throughput real de processamento.
+	closeSend()
+	seqWg.Wait()
+	elapsed = time.Since(start)
+
+	return criticalTime, counter, elapsed
+}
+
+// newUnboundedChannel emula um canal sem limite de buffer: uma goroutine
+// acumula os valores recebidos de 'in' em um slice que cresce sob demanda,
+// repassando-os para 'out' assim que houver um receptor pronto. Diferente
+// de um chan com make(chan T, N), o envio em 'in' nunca bloqueia esperando
+// espaço no buffer.
+func newUnboundedChannel() (chan<- int64, <-chan int64) {
+	in := make(chan int64)
+	out := make(chan int64)
+
+	go func() {
+		defer close(out)
+
+		var queue []int64
+		for {
+			if len(queue) == 0 {
+				v, ok := <-in
+				if !ok {
+					return
+				}
+				queue = append(queue, v)
+				continue
+			}
+
+			select {
+			case v, ok := <-in:
+				if !ok {
+					for _, q := range queue {
+						out <- q
+					}
+					return
+				}
+				queue = append(queue, v)
+			case out <- queue[0]:
+				queue = queue[1:]
+			}
+		}
+	}()
+
+	return in, out
+}
+
+// tTable95 guarda o valor crítico de t de Student bicaudal a 95% de
+// confiança para graus de liberdade comuns (1 a 30). Para df > 30, a
+// aproximação normal z=1.96 é usada em seu lugar.
+var tTable95 = map[int]float64{
+	1: 12.706, 2: 4.303, 3: 3.182, 4: 2.776, 5: 2.571,
+	6: 2.447, 7: 2.365, 8: 2.306, 9: 2.262, 10: 2.228,
+	11: 2.201, 12: 2.179, 13: 2.160, 14: 2.145, 15: 2.131,
+	16: 2.120, 17: 2.110, 18: 2.101, 19: 2.093, 20: 2.086,
+	21: 2.080, 22: 2.074, 23: 2.069, 24: 2.064, 25: 2.060,
+	26: 2.056, 27: 2.052, 28: 2.048, 29: 2.045, 30: 2.042,
+}
+
+// criticalValue retorna o valor crítico de t (ou a aproximação normal
+// z=1.96 para df>30) usado para montar o intervalo de confiança de 95%.
+func criticalValue(df int) float64 {
+	if df < 1 {
+		return tTable95[1]
+	}
+	if v, ok := tTable95[df]; ok {
+		return v
+	}
+	return 1.96
+}
+
+// computeStat calcula média, desvio padrão amostral e intervalo de
+// confiança de 95% (t de Student para n pequeno, aproximação normal para
+// n>31, isto é, df=n-1>30) de uma amostra.
+func computeStat(samples []float64) SweepStat {
+	n := len(samples)
+	mean := meanOf(samples)
+	stddev := stddevOf(samples, mean)
+
+	if n < 2 {
+		return SweepStat{Mean: mean, Stddev: stddev, CILow: mean, CIHigh: mean}
+	}
+
+	standardError := stddev / math.Sqrt(float64(n))
+	margin := criticalValue(n-1) * standardError
+
+	return SweepStat{Mean: mean, Stddev: stddev, CILow: mean - margin, CIHigh: mean + margin}
+}
+
+func meanOf(samples []float64) float64 {
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	return sum / float64(len(samples))
+}
+
+func stddevOf(samples []float64, mean float64) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	var sumSquares float64
+	for _, v := range samples {
+		d := v - mean
+		sumSquares += d * d
+	}
+
+	return math.Sqrt(sumSquares / float64(len(samples)-1))
+}
+
+// bufferSizeLabel formata um tamanho de buffer para exibição, usando
+// "unbounded" no lugar do sentinel unboundedBufferSize.
+func bufferSizeLabel(bufferSize int) string {
+	if bufferSize == unboundedBufferSize {
+		return "unbounded"
+	}
+	return strconv.Itoa(bufferSize)
+}
+
+// printSweepTable imprime a grade de varredura completa em uma tabela
+// alinhada com text/tabwriter: um ponto da grade por linha.
+func printSweepTable(results []SweepResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "\n--- Varredura de Buffer/GOMAXPROCS/Workers ---\n")
+	fmt.Fprintln(w, "workers\tbuffer\tgomaxprocs\tthroughput média (ops/s)\tIC95%\tseção crítica média (s)\tIC95%")
+
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.2f\t[%.2f, %.2f]\t%.9f\t[%.9f, %.9f]\n",
+			r.Cell.Workers, bufferSizeLabel(r.Cell.BufferSize), r.Cell.GOMAXPROCS,
+			r.Throughput.Mean, r.Throughput.CILow, r.Throughput.CIHigh,
+			r.CriticalTime.Mean, r.CriticalTime.CILow, r.CriticalTime.CIHigh)
+	}
+}
+
+// writeSweepJSON grava a grade de varredura completa, com todas as
+// estatísticas, em JSON legível por ferramentas de plotagem.
+func writeSweepJSON(path string, results []SweepResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("criar arquivo: %w", err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// writeSweepCSV grava a grade de varredura completa, uma linha por ponto,
+// em CSV.
+func writeSweepCSV(path string, results []SweepResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("criar arquivo: %w", err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	defer writer.Flush()
+
+	header := []string{
+		"workers", "buffer_size", "gomaxprocs",
+		"throughput_mean_ops_s", "throughput_ci95_low", "throughput_ci95_high",
+		"critical_time_mean_s", "critical_time_ci95_low", "critical_time_ci95_high",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("escrever cabeçalho: %w", err)
+	}
+
+	for _, r := range results {
+		record := []string{
+			strconv.Itoa(r.Cell.Workers),
+			bufferSizeLabel(r.Cell.BufferSize),
+			strconv.Itoa(r.Cell.GOMAXPROCS),
+			strconv.FormatFloat(r.Throughput.Mean, 'f', -1, 64),
+			strconv.FormatFloat(r.Throughput.CILow, 'f', -1, 64),
+			strconv.FormatFloat(r.Throughput.CIHigh, 'f', -1, 64),
+			strconv.FormatFloat(r.CriticalTime.Mean, 'f', -1, 64),
+			strconv.FormatFloat(r.CriticalTime.CILow, 'f', -1, 64),
+			strconv.FormatFloat(r.CriticalTime.CIHigh, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("escrever linha: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"runtime/trace"
+	"sync"
+	"time"
+)
+
+// topologyKind enumera as topologias de fan-in suportadas pelo benchmark.
+// Todas preservam a garantia do Sequencer (um único escritor por shard), mas
+// variam a forma como as requisições dos workers chegam até os sequencers.
+type topologyKind string
+
+const (
+	// topologyFanIn é a topologia original: todos os workers enviam para um
+	// único sequencer.
+	topologyFanIn topologyKind = "fanin"
+
+	// topologySharded distribui os workers entre N sequencers independentes.
+	// O laço que dispara os workers calcula o hash do seed de cada um e o
+	// worker envia diretamente para o shard correspondente.
+	topologySharded topologyKind = "sharded"
+
+	// topologyPipelined intercala um estágio "hasher" entre os workers e os
+	// sequencers: os workers enviam para um canal comum e uma goroutine
+	// dedicada decide, via hash, para qual shard encaminhar cada requisição.
+	topologyPipelined topologyKind = "pipelined"
+)
+
+// parseTopologyKind converte o valor da flag -topology em um topologyKind
+// válido, retornando um erro caso o valor não seja reconhecido.
+func parseTopologyKind(value string) (topologyKind, error) {
+	switch topologyKind(value) {
+	case topologyFanIn, topologySharded, topologyPipelined:
+		return topologyKind(value), nil
+	default:
+		return "", fmt.Errorf("topologia desconhecida: %q (use fanin, sharded ou pipelined)", value)
+	}
+}
+
+// shard é uma instância do padrão Sequencer: um canal de requisições e o
+// estado (counter, tempo de seção crítica, junk) que só a própria goroutine
+// sequenciadora do shard modifica.
+type shard struct {
+	requests     chan int64
+	counter      int64
+	criticalTime time.Duration
+
+	// junk é o equivalente, por shard, do junkValue global usado pelas
+	// demais variantes: garante que o compilador não otimize e remova o
+	// loop de processamento simulado. Precisa ser por shard (em vez de um
+	// único junkValue global) porque, com numShards > 1, os sequencers de
+	// cada shard rodam concorrentemente; escrever em uma variável
+	// compartilhada sem sincronização seria uma condição de corrida.
+	junk int64
+}
+
+func newShard() *shard {
+	return &shard{requests: make(chan int64)}
+}
+
+// run processa as requisições do shard serialmente, na própria goroutine
+// sequenciadora, até que o canal seja fechado. label identifica o shard na
+// região de trace, permitindo distinguir a contenção de cada um em uma
+// topologia com múltiplos shards.
+func (s *shard) run(wg *sync.WaitGroup, loopIntensity int, ctx context.Context, label string) {
+	defer wg.Done()
+
+	for requestSeed := range s.requests {
+		// --- INÍCIO DA SEÇÃO CRÍTICA ---
+		region := trace.StartRegion(ctx, "critical-section:"+label)
+		start := time.Now()
+
+		// Simula um processamento de CPU intensivo.
+		result := requestSeed
+		for i := 0; i < loopIntensity; i++ {
+			result = result*31 + int64(i)
+		}
+
+		// Modificação segura do estado do shard. Como apenas a goroutine do
+		// shard executa este código, não há risco de condição de corrida.
+		s.junk = result
+		s.counter++
+
+		s.criticalTime += time.Since(start)
+		region.End()
+		// --- FIM DA SEÇÃO CRÍTICA ---
+	}
+}
+
+// worker representa uma goroutine "produtora". Ela gera uma única unidade
+// de trabalho e a envia para 'requests' (um shard, ou o estágio de hash de
+// uma topologia pipelined). ctx é usado para anotar o envio com uma região
+// e um evento de runtime/trace.
+func worker(wg *sync.WaitGroup, requests chan<- int64, seed int64, ctx context.Context) {
+	defer wg.Done()
+
+	region := trace.StartRegion(ctx, "worker-send")
+	defer region.End()
+
+	trace.Log(ctx, "worker-send", fmt.Sprintf("seed=%d", seed))
+
+	// Envia um valor para 'requests'. A execução deste worker irá *bloquear*
+	// nesta linha até que o lado receptor esteja pronto para receber, o que
+	// naturalmente limita a velocidade dos workers à capacidade de
+	// processamento do(s) sequencer(s).
+	requests <- seed
+}
+
+// hashShard calcula, via FNV-1a, para qual shard (de 0 a numShards-1) o
+// seed de uma requisição deve ser roteado.
+func hashShard(seed int64, numShards int) int {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(seed))
+
+	h := fnv.New64a()
+	h.Write(buf[:])
+
+	return int(h.Sum64() % uint64(numShards))
+}
+
+// roundOutcome é o resultado agregado de uma rodada do benchmark,
+// independente de qual topologia a produziu.
+type roundOutcome struct {
+	// shardCounters guarda o valor final do counter de cada shard, usado
+	// para verificar se ocorreram condições de corrida.
+	shardCounters []int64
+
+	// criticalTime é a soma do tempo de seção crítica de todos os shards.
+	criticalTime time.Duration
+}
+
+// runTopologyRound executa uma rodada completa do benchmark na topologia
+// selecionada.
+func runTopologyRound(kind topologyKind, numWorkers, loopIntensity, numShards int, ctx context.Context) roundOutcome {
+	switch kind {
+	case topologySharded:
+		return runShardedRound(numWorkers, loopIntensity, numShards, ctx)
+	case topologyPipelined:
+		return runPipelinedRound(numWorkers, loopIntensity, numShards, ctx)
+	default:
+		return runFanInRound(numWorkers, loopIntensity, ctx)
+	}
+}
+
+// runFanInRound reproduz a topologia original: um único shard recebendo de
+// todos os workers.
+func runFanInRound(numWorkers, loopIntensity int, ctx context.Context) roundOutcome {
+	s := newShard()
+
+	var shardWg sync.WaitGroup
+	shardWg.Add(1)
+	go s.run(&shardWg, loopIntensity, ctx, "0")
+
+	var workersWg sync.WaitGroup
+	workersWg.Add(numWorkers)
+	for j := 0; j < numWorkers; j++ {
+		go worker(&workersWg, s.requests, time.Now().UnixNano(), ctx)
+	}
+	workersWg.Wait()
+
+	close(s.requests)
+	shardWg.Wait()
+
+	return roundOutcome{shardCounters: []int64{s.counter}, criticalTime: s.criticalTime}
+}
+
+// runShardedRound distribui os workers entre numShards sequencers. O shard
+// de destino de cada worker é calculado, via hashShard, no laço que o
+// dispara; o worker envia diretamente a ele, sem um estágio intermediário.
+func runShardedRound(numWorkers, loopIntensity, numShards int, ctx context.Context) roundOutcome {
+	shards := make([]*shard, numShards)
+	var shardsWg sync.WaitGroup
+	shardsWg.Add(numShards)
+	for i := range shards {
+		shards[i] = newShard()
+		go shards[i].run(&shardsWg, loopIntensity, ctx, fmt.Sprintf("%d", i))
+	}
+
+	var workersWg sync.WaitGroup
+	workersWg.Add(numWorkers)
+	for j := 0; j < numWorkers; j++ {
+		seed := time.Now().UnixNano()
+		target := shards[hashShard(seed, numShards)]
+		go worker(&workersWg, target.requests, seed, ctx)
+	}
+	workersWg.Wait()
+
+	for _, s := range shards {
+		close(s.requests)
+	}
+	shardsWg.Wait()
+
+	return collectShardOutcome(shards)
+}
+
+// runPipelinedRound intercala um estágio "hasher" entre os workers e os
+// sequencers: os workers enviam para um canal comum e uma goroutine
+// dedicada roteia cada requisição ao shard correspondente.
+func runPipelinedRound(numWorkers, loopIntensity, numShards int, ctx context.Context) roundOutcome {
+	shards := make([]*shard, numShards)
+	var shardsWg sync.WaitGroup
+	shardsWg.Add(numShards)
+	for i := range shards {
+		shards[i] = newShard()
+		go shards[i].run(&shardsWg, loopIntensity, ctx, fmt.Sprintf("%d", i))
+	}
+
+	dispatch := make(chan int64)
+	var hasherWg sync.WaitGroup
+	hasherWg.Add(1)
+	go runHasher(&hasherWg, dispatch, shards, ctx)
+
+	var workersWg sync.WaitGroup
+	workersWg.Add(numWorkers)
+	for j := 0; j < numWorkers; j++ {
+		go worker(&workersWg, dispatch, time.Now().UnixNano(), ctx)
+	}
+	workersWg.Wait()
+
+	close(dispatch)
+	hasherWg.Wait()
+
+	for _, s := range shards {
+		close(s.requests)
+	}
+	shardsWg.Wait()
+
+	return collectShardOutcome(shards)
+}
+
+// runHasher implementa o estágio intermediário da topologia pipelined:
+// recebe requisições dos workers por 'dispatch' e as encaminha ao shard
+// apropriado com base em hashShard.
+func runHasher(wg *sync.WaitGroup, dispatch <-chan int64, shards []*shard, ctx context.Context) {
+	defer wg.Done()
+
+	for seed := range dispatch {
+		region := trace.StartRegion(ctx, "hasher-dispatch")
+		target := shards[hashShard(seed, len(shards))]
+		target.requests <- seed
+		region.End()
+	}
+}
+
+// collectShardOutcome agrega o counter e o tempo de seção crítica finais de
+// um conjunto de shards já finalizados em um roundOutcome.
+func collectShardOutcome(shards []*shard) roundOutcome {
+	counters := make([]int64, len(shards))
+	var totalCritical time.Duration
+	for i, s := range shards {
+		counters[i] = s.counter
+		totalCritical += s.criticalTime
+	}
+	return roundOutcome{shardCounters: counters, criticalTime: totalCritical}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+)
+
+// startRoundTrace inicia, quando habilitado pela flag -trace, uma captura de
+// runtime/trace para uma única rodada, gravando em
+// "<traceDir>/round-<round>.trace". A função retornada deve ser chamada ao
+// final da rodada para parar a captura e fechar o arquivo; quando o
+// tracing está desabilitado (ou não pôde ser iniciado), ela é um no-op.
+func startRoundTrace(enabled bool, dir string, round int) (stop func()) {
+	if !enabled {
+		return func() {}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "trace: não foi possível criar o diretório %q: %v\n", dir, err)
+		return func() {}
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("round-%d.trace", round))
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: não foi possível criar %q: %v\n", path, err)
+		return func() {}
+	}
+
+	if err := trace.Start(f); err != nil {
+		fmt.Fprintf(os.Stderr, "trace: não foi possível iniciar a captura: %v\n", err)
+		f.Close()
+		return func() {}
+	}
+
+	return func() {
+		trace.Stop()
+		f.Close()
+	}
+}
@@ -0,0 +1,126 @@
+package bench
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CPUQuotaSource identifica de onde veio o número de núcleos usado para
+// normalizar AverageCPUNormalized.
+type CPUQuotaSource string
+
+const (
+	// CPUQuotaSourceCgroupV2 indica que a cota veio de cpu.max (cgroup v2).
+	CPUQuotaSourceCgroupV2 CPUQuotaSource = "cgroup v2 (cpu.max)"
+
+	// CPUQuotaSourceCgroupV1 indica que a cota veio de cpu.cfs_quota_us /
+	// cpu.cfs_period_us (cgroup v1).
+	CPUQuotaSourceCgroupV1 CPUQuotaSource = "cgroup v1 (cpu.cfs_quota_us/cpu.cfs_period_us)"
+
+	// CPUQuotaSourceNumCPU indica que nenhum limite de cgroup foi
+	// encontrado e o número de núcleos do host (runtime.NumCPU()) foi
+	// usado no lugar.
+	CPUQuotaSourceNumCPU CPUQuotaSource = "runtime.NumCPU (sem limite de cgroup)"
+)
+
+// CPUQuota é o número efetivo de núcleos disponíveis para este processo,
+// usado para normalizar AverageCPUNormalized. Em um container sem limite
+// de CPU, ou rodando fora de container, Cores é runtime.NumCPU().
+type CPUQuota struct {
+	Cores  float64        `json:"cores"`
+	Source CPUQuotaSource `json:"source"`
+}
+
+// cgroupQueryer sonda um layout de contabilização de CPU de cgroup
+// (v1 ou v2) em busca de uma cota efetiva.
+type cgroupQueryer interface {
+	// Quota retorna o número efetivo de núcleos e true se este layout de
+	// cgroup está presente e relata uma cota limitada (não "max"/ilimitada).
+	Quota() (cores float64, ok bool)
+}
+
+// cgroupV2Queryer lê a cota de CPU de um arquivo cpu.max de cgroup v2, no
+// formato "<quota> <period>" (ou "max <period>" quando ilimitado).
+type cgroupV2Queryer struct {
+	path string
+}
+
+func (q cgroupV2Queryer) Quota() (float64, bool) {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return 0, false
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+
+	return quota / period, true
+}
+
+// cgroupV1Queryer lê a cota de CPU de um par de arquivos cpu.cfs_quota_us
+// / cpu.cfs_period_us de cgroup v1. Uma cota de -1 significa ilimitada.
+type cgroupV1Queryer struct {
+	quotaPath  string
+	periodPath string
+}
+
+func (q cgroupV1Queryer) Quota() (float64, bool) {
+	quotaRaw, err := os.ReadFile(q.quotaPath)
+	if err != nil {
+		return 0, false
+	}
+	periodRaw, err := os.ReadFile(q.periodPath)
+	if err != nil {
+		return 0, false
+	}
+
+	quota, err := strconv.ParseFloat(strings.TrimSpace(string(quotaRaw)), 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseFloat(strings.TrimSpace(string(periodRaw)), 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+
+	return quota / period, true
+}
+
+// DetectCPUQuota sonda cgroup v2 e depois cgroup v1 em busca de uma cota de
+// CPU efetiva, caindo de volta para runtime.NumCPU() quando nenhum dos dois
+// relata um limite (bare metal, container sem limite de CPU, ou SO sem
+// suporte a cgroups). O resultado é usado para normalizar
+// AverageCPUNormalized corretamente em containers com CPU fracionária.
+func DetectCPUQuota() CPUQuota {
+	queryers := []struct {
+		q      cgroupQueryer
+		source CPUQuotaSource
+	}{
+		{cgroupV2Queryer{path: "/sys/fs/cgroup/cpu.max"}, CPUQuotaSourceCgroupV2},
+		{cgroupV1Queryer{
+			quotaPath:  "/sys/fs/cgroup/cpu/cpu.cfs_quota_us",
+			periodPath: "/sys/fs/cgroup/cpu/cpu.cfs_period_us",
+		}, CPUQuotaSourceCgroupV1},
+	}
+
+	for _, c := range queryers {
+		if cores, ok := c.q.Quota(); ok {
+			return CPUQuota{Cores: cores, Source: c.source}
+		}
+	}
+
+	return CPUQuota{Cores: float64(runtime.NumCPU()), Source: CPUQuotaSourceNumCPU}
+}
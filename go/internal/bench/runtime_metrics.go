@@ -0,0 +1,256 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"sort"
+)
+
+// Nomes das métricas do runtime/metrics amostradas a cada rodada do
+// benchmark. A variante channel-sequencer usa bloqueio de canal em vez de
+// sync.Mutex, então a métrica de espera por mutex tende a ficar zerada para
+// ela; ela é mantida porque as variantes sync.Mutex e sync/atomic
+// compartilham este mesmo harness de coleta.
+const (
+	metricMutexWaitTotal  = "/sync/mutex/wait/total:seconds"
+	metricSchedLatencies  = "/sched/latencies:seconds"
+	metricSchedGoroutines = "/sched/goroutines:goroutines"
+	metricGCMarkAssist    = "/cpu/classes/gc/mark/assist:cpu-seconds"
+	metricGCMarkDedicated = "/cpu/classes/gc/mark/dedicated:cpu-seconds"
+	metricGCMarkIdle      = "/cpu/classes/gc/mark/idle:cpu-seconds"
+	metricGCPause         = "/cpu/classes/gc/pause:cpu-seconds"
+	metricGCTotal         = "/cpu/classes/gc/total:cpu-seconds"
+)
+
+// runtimeMetricNames lista as métricas lidas a cada amostragem. A ordem não
+// importa para metrics.Read, mas mantê-la estável facilita comparar o slice
+// de amostras entre chamadas.
+var runtimeMetricNames = []string{
+	metricMutexWaitTotal,
+	metricSchedLatencies,
+	metricSchedGoroutines,
+	metricGCMarkAssist,
+	metricGCMarkDedicated,
+	metricGCMarkIdle,
+	metricGCPause,
+	metricGCTotal,
+}
+
+// schedLatencyHistogram é uma cópia independente de um
+// metrics.Float64Histogram. O runtime pode reaproveitar a memória apontada
+// por um histograma retornado por metrics.Read na próxima chamada, então os
+// slices precisam ser copiados antes de guardar o snapshot para comparação
+// posterior.
+type schedLatencyHistogram struct {
+	buckets []float64
+	counts  []uint64
+}
+
+// RuntimeSnapshot guarda os valores cumulativos do runtime/metrics
+// capturados em um único instante. A diferença entre dois snapshots (antes e
+// depois de uma rodada) produz os deltas exibidos nos relatórios.
+type RuntimeSnapshot struct {
+	mutexWaitSeconds float64
+	schedGoroutines  uint64
+	gcCPUSeconds     map[string]float64
+	schedLatencies   schedLatencyHistogram
+}
+
+// NewRuntimeMetricSamples aloca o slice de metrics.Sample reaproveitado em
+// todas as chamadas de metrics.Read do benchmark, evitando realocações a
+// cada rodada.
+func NewRuntimeMetricSamples() []metrics.Sample {
+	samples := make([]metrics.Sample, len(runtimeMetricNames))
+	for i, name := range runtimeMetricNames {
+		samples[i].Name = name
+	}
+	return samples
+}
+
+// ReadRuntimeSnapshot lê as métricas em 'samples' (reaproveitado entre
+// chamadas) e retorna uma cópia independente dos valores de interesse.
+func ReadRuntimeSnapshot(samples []metrics.Sample) RuntimeSnapshot {
+	metrics.Read(samples)
+
+	snap := RuntimeSnapshot{
+		gcCPUSeconds: make(map[string]float64, 5),
+	}
+
+	for _, s := range samples {
+		switch s.Name {
+		case metricMutexWaitTotal:
+			snap.mutexWaitSeconds = s.Value.Float64()
+		case metricSchedGoroutines:
+			snap.schedGoroutines = s.Value.Uint64()
+		case metricSchedLatencies:
+			hist := s.Value.Float64Histogram()
+			snap.schedLatencies = schedLatencyHistogram{
+				buckets: append([]float64(nil), hist.Buckets...),
+				counts:  append([]uint64(nil), hist.Counts...),
+			}
+		case metricGCMarkAssist, metricGCMarkDedicated, metricGCMarkIdle, metricGCPause, metricGCTotal:
+			snap.gcCPUSeconds[s.Name] = s.Value.Float64()
+		}
+	}
+
+	return snap
+}
+
+// SchedLatencyPercentiles resume a distribuição de latências de
+// escalonamento (tempo entre uma goroutine ficar pronta para rodar e
+// efetivamente começar a rodar) observada durante uma rodada.
+type SchedLatencyPercentiles struct {
+	Min, P50, P95, P99 float64
+}
+
+// RoundMetrics é o delta do runtime/metrics atribuível a uma única rodada do
+// benchmark, calculado a partir de dois RuntimeSnapshot (antes/depois).
+// A exceção é SchedGoroutines, que não é um delta: é o número de goroutines
+// vivas no snapshot tirado ao fim da rodada.
+type RoundMetrics struct {
+	MutexWaitSeconds float64
+	SchedGoroutines  uint64
+	GCCPUSeconds     map[string]float64
+	SchedLatency     SchedLatencyPercentiles
+}
+
+// DiffRuntimeSnapshot calcula o delta de uma rodada a partir dos snapshots
+// tirados imediatamente antes e depois dela.
+func DiffRuntimeSnapshot(before, after RuntimeSnapshot) RoundMetrics {
+	gcDelta := make(map[string]float64, len(after.gcCPUSeconds))
+	for name, v := range after.gcCPUSeconds {
+		gcDelta[name] = v - before.gcCPUSeconds[name]
+	}
+
+	return RoundMetrics{
+		MutexWaitSeconds: after.mutexWaitSeconds - before.mutexWaitSeconds,
+		SchedGoroutines:  after.schedGoroutines,
+		GCCPUSeconds:     gcDelta,
+		SchedLatency:     diffSchedLatencies(before.schedLatencies, after.schedLatencies),
+	}
+}
+
+// diffSchedLatencies subtrai as contagens de 'before' de 'after' bucket a
+// bucket e calcula min/p50/p95/p99 da distribuição resultante. Os limites de
+// bucket de 'before' e 'after' são os mesmos, pois o runtime não os altera
+// durante a vida do processo.
+func diffSchedLatencies(before, after schedLatencyHistogram) SchedLatencyPercentiles {
+	if len(after.counts) == 0 || len(after.counts) != len(before.counts) {
+		return SchedLatencyPercentiles{}
+	}
+
+	counts := make([]uint64, len(after.counts))
+	var total uint64
+	for i := range counts {
+		counts[i] = after.counts[i] - before.counts[i]
+		total += counts[i]
+	}
+
+	if total == 0 {
+		return SchedLatencyPercentiles{}
+	}
+
+	buckets := after.buckets
+
+	return SchedLatencyPercentiles{
+		Min: minBucketBoundary(buckets, counts),
+		P50: bucketPercentile(buckets, counts, total, 0.50),
+		P95: bucketPercentile(buckets, counts, total, 0.95),
+		P99: bucketPercentile(buckets, counts, total, 0.99),
+	}
+}
+
+// minBucketBoundary retorna o limite inferior do primeiro bucket com
+// contagem não nula, usado como aproximação do valor mínimo observado na
+// rodada.
+func minBucketBoundary(buckets []float64, counts []uint64) float64 {
+	for i, c := range counts {
+		if c > 0 {
+			return buckets[i]
+		}
+	}
+	return 0
+}
+
+// bucketPercentile interpola linearmente dentro do bucket que contém o rank
+// desejado (p * total de observações). É uma aproximação: o histograma do
+// runtime/metrics guarda apenas contagens por faixa, não os valores
+// individuais.
+func bucketPercentile(buckets []float64, counts []uint64, total uint64, p float64) float64 {
+	target := p * float64(total)
+
+	var cumulative uint64
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		if float64(cumulative+c) >= target {
+			lo, hi := buckets[i], buckets[i+1]
+			if math.IsInf(hi, 0) {
+				return lo
+			}
+			frac := (target - float64(cumulative)) / float64(c)
+			return lo + frac*(hi-lo)
+		}
+		cumulative += c
+	}
+
+	return buckets[len(buckets)-1]
+}
+
+// PrintRuntimeMetricsReport imprime, por rodada e em média, os deltas do
+// runtime/metrics coletados durante um benchmark. O histograma de latências
+// de escalonamento é frequentemente o sinal mais informativo para variantes
+// baseadas em canal/goroutine, já que elas não geram espera por mutex.
+func PrintRuntimeMetricsReport(rounds []RoundMetrics) {
+	if len(rounds) == 0 {
+		return
+	}
+
+	fmt.Printf("\n--- Métricas de runtime/metrics por rodada ---\n")
+
+	gcClassNames := sortedGCClassNames(rounds[0].GCCPUSeconds)
+
+	for i, r := range rounds {
+		fmt.Printf("Rodada %d: espera por mutex=%.9fs, goroutines=%d, latência de escalonamento [min=%.9fs p50=%.9fs p95=%.9fs p99=%.9fs]\n",
+			i, r.MutexWaitSeconds, r.SchedGoroutines,
+			r.SchedLatency.Min, r.SchedLatency.P50, r.SchedLatency.P95, r.SchedLatency.P99)
+
+		for _, name := range gcClassNames {
+			fmt.Printf("  CPU de GC %s: %.9fs\n", name, r.GCCPUSeconds[name])
+		}
+	}
+
+	var totalMutexWait, totalP50, totalP95, totalP99 float64
+	totalGC := make(map[string]float64, len(gcClassNames))
+	for _, r := range rounds {
+		totalMutexWait += r.MutexWaitSeconds
+		totalP50 += r.SchedLatency.P50
+		totalP95 += r.SchedLatency.P95
+		totalP99 += r.SchedLatency.P99
+		for _, name := range gcClassNames {
+			totalGC[name] += r.GCCPUSeconds[name]
+		}
+	}
+
+	n := float64(len(rounds))
+	fmt.Printf("\n--- Médias de runtime/metrics ---\n")
+	fmt.Printf("Espera média por mutex por rodada: %.9fs\n", totalMutexWait/n)
+	fmt.Printf("Latência média de escalonamento por rodada: p50=%.9fs p95=%.9fs p99=%.9fs\n",
+		totalP50/n, totalP95/n, totalP99/n)
+	for _, name := range gcClassNames {
+		fmt.Printf("CPU médio de GC (%s) por rodada: %.9fs\n", name, totalGC[name]/n)
+	}
+}
+
+// sortedGCClassNames retorna as chaves de um mapa de classes de CPU de GC em
+// ordem estável, para que a saída do relatório não varie entre execuções.
+func sortedGCClassNames(classes map[string]float64) []string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,66 @@
+package variants
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/bench"
+)
+
+// junkValueAtomic é o equivalente de junkValue para AtomicCounter: como os
+// workers desta variante rodam totalmente em paralelo (sem lock algum), uma
+// variável simples seria uma condição de corrida, então o valor é guardado
+// atomicamente.
+var junkValueAtomic atomic.Int64
+
+// AtomicCounter incrementa um contador com sync/atomic, sem nenhum lock.
+// Não há seção crítica no sentido de exclusão mútua; o "tempo de seção
+// crítica" relatado é o tempo gasto no processamento simulado de CPU mais a
+// própria operação atômica, somado entre todos os workers.
+type AtomicCounter struct {
+	cfg bench.Config
+}
+
+// Name identifica esta variante nos relatórios.
+func (a *AtomicCounter) Name() string { return "sync/atomic" }
+
+// Setup guarda a configuração usada pelas rodadas subsequentes.
+func (a *AtomicCounter) Setup(cfg bench.Config) { a.cfg = cfg }
+
+// Teardown não tem recursos para liberar nesta variante.
+func (a *AtomicCounter) Teardown() {}
+
+// RunRound dispara cfg.NumWorkers goroutines que incrementam
+// concorrentemente um atomic.Int64, sem nenhuma serialização entre elas.
+func (a *AtomicCounter) RunRound(ctx context.Context) bench.RoundResult {
+	var counter atomic.Int64
+	var criticalNanos atomic.Int64
+
+	var wg sync.WaitGroup
+	wg.Add(a.cfg.NumWorkers)
+	for j := 0; j < a.cfg.NumWorkers; j++ {
+		go func(seed int64) {
+			defer wg.Done()
+
+			start := time.Now()
+
+			result := seed
+			for i := 0; i < a.cfg.LoopIntensity; i++ {
+				result = result*31 + int64(i)
+			}
+
+			junkValueAtomic.Store(result)
+			counter.Add(1)
+
+			criticalNanos.Add(int64(time.Since(start)))
+		}(time.Now().UnixNano())
+	}
+	wg.Wait()
+
+	return bench.RoundResult{
+		FinalCount:   counter.Load(),
+		CriticalTime: time.Duration(criticalNanos.Load()),
+	}
+}
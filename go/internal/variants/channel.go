@@ -0,0 +1,87 @@
+// Package variants contém as implementações de bench.Benchmark comparadas
+// pelo binário cmd/compare: um sequencer baseado em canal (o padrão usado
+// pelo binário cmd/csp), um contador guardado por sync.Mutex e um contador
+// baseado em sync/atomic.
+package variants
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/bench"
+)
+
+// junkValue é usado para garantir que o compilador não otimize e remova o
+// loop de processamento da seção crítica, assegurando que o trabalho de CPU
+// simulado aconteça. É seguro como variável simples para as variantes
+// ChannelSequencer e MutexCounter porque, em ambas, apenas uma goroutine por
+// vez a escreve (o sequencer, ou o detentor do mutex); AtomicCounter usa seu
+// próprio contador atômico para o mesmo propósito.
+var junkValue int64
+
+// ChannelSequencer reproduz o padrão Sequencer: um único canal sem buffer
+// onde todos os workers enviam sua requisição, e uma goroutine dedicada (o
+// "sequencer") que as processa serialmente.
+type ChannelSequencer struct {
+	cfg bench.Config
+}
+
+// Name identifica esta variante nos relatórios.
+func (c *ChannelSequencer) Name() string { return "channel-sequencer" }
+
+// Setup guarda a configuração usada pelas rodadas subsequentes.
+func (c *ChannelSequencer) Setup(cfg bench.Config) { c.cfg = cfg }
+
+// Teardown não tem recursos para liberar nesta variante.
+func (c *ChannelSequencer) Teardown() {}
+
+// RunRound cria um sequencer e cfg.NumWorkers workers, aguarda todos
+// enviarem sua requisição e retorna o counter final e o tempo de seção
+// crítica acumulado.
+func (c *ChannelSequencer) RunRound(ctx context.Context) bench.RoundResult {
+	requests := make(chan int64)
+
+	var counter int64
+	var criticalTime time.Duration
+
+	var sequencerWg sync.WaitGroup
+	sequencerWg.Add(1)
+	go func() {
+		defer sequencerWg.Done()
+
+		// O loop 'for range' em um canal continuará a receber valores até
+		// que o canal seja fechado e todos os valores tenham sido
+		// processados.
+		for requestSeed := range requests {
+			// --- INÍCIO DA SEÇÃO CRÍTICA ---
+			start := time.Now()
+
+			result := requestSeed
+			for i := 0; i < c.cfg.LoopIntensity; i++ {
+				result = result*31 + int64(i)
+			}
+
+			junkValue = result
+			counter++
+
+			criticalTime += time.Since(start)
+			// --- FIM DA SEÇÃO CRÍTICA ---
+		}
+	}()
+
+	var workersWg sync.WaitGroup
+	workersWg.Add(c.cfg.NumWorkers)
+	for j := 0; j < c.cfg.NumWorkers; j++ {
+		go func(seed int64) {
+			defer workersWg.Done()
+			requests <- seed
+		}(time.Now().UnixNano())
+	}
+	workersWg.Wait()
+
+	close(requests)
+	sequencerWg.Wait()
+
+	return bench.RoundResult{FinalCount: counter, CriticalTime: criticalTime}
+}
@@ -0,0 +1,60 @@
+package variants
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Juuliocq/csp-mutex-comparison/go/internal/bench"
+)
+
+// MutexCounter protege um counter compartilhado com um sync.Mutex comum: a
+// seção crítica (o incremento e o processamento simulado de CPU) roda sob o
+// lock, com todos os workers concorrendo diretamente por ele.
+type MutexCounter struct {
+	cfg bench.Config
+}
+
+// Name identifica esta variante nos relatórios.
+func (m *MutexCounter) Name() string { return "sync.Mutex" }
+
+// Setup guarda a configuração usada pelas rodadas subsequentes.
+func (m *MutexCounter) Setup(cfg bench.Config) { m.cfg = cfg }
+
+// Teardown não tem recursos para liberar nesta variante.
+func (m *MutexCounter) Teardown() {}
+
+// RunRound dispara cfg.NumWorkers goroutines que disputam o mesmo
+// sync.Mutex para incrementar o counter compartilhado.
+func (m *MutexCounter) RunRound(ctx context.Context) bench.RoundResult {
+	var mu sync.Mutex
+	var counter int64
+	var criticalTime time.Duration
+
+	var wg sync.WaitGroup
+	wg.Add(m.cfg.NumWorkers)
+	for j := 0; j < m.cfg.NumWorkers; j++ {
+		go func(seed int64) {
+			defer wg.Done()
+
+			// --- INÍCIO DA SEÇÃO CRÍTICA ---
+			mu.Lock()
+			start := time.Now()
+
+			result := seed
+			for i := 0; i < m.cfg.LoopIntensity; i++ {
+				result = result*31 + int64(i)
+			}
+
+			junkValue = result
+			counter++
+
+			criticalTime += time.Since(start)
+			mu.Unlock()
+			// --- FIM DA SEÇÃO CRÍTICA ---
+		}(time.Now().UnixNano())
+	}
+	wg.Wait()
+
+	return bench.RoundResult{FinalCount: counter, CriticalTime: criticalTime}
+}